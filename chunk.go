@@ -0,0 +1,196 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/minio/minio-go/v7"
+)
+
+// chunkTask describes a single byte-range to fetch within an object.
+type chunkTask struct {
+	offset int64
+	length int64
+}
+
+// chunkSpan is a downloaded range, backed by a buffer borrowed from an
+// arenaPool. Its offset lets completed spans be reordered before they are
+// streamed out. data is always task.length long regardless of outcome, so
+// offset bookkeeping during reassembly stays correct; n is how many bytes
+// were actually read (0 on failure) and is what should be counted towards
+// the object's downloaded size. firstStatusCode and retries mirror the
+// corresponding ChannelSample fields and are rolled up into the
+// whole-object sample once every chunk has been reassembled.
+type chunkSpan struct {
+	offset          int64
+	data            []byte
+	n               int
+	statusCode      int
+	firstStatusCode int
+	retries         int
+}
+
+// chunkHeap is a min-heap of chunkSpans ordered by offset, so a span that
+// completes out of order waits until its predecessor has been streamed.
+type chunkHeap []chunkSpan
+
+func (h chunkHeap) Len() int           { return len(h) }
+func (h chunkHeap) Less(i, j int) bool { return h[i].offset < h[j].offset }
+func (h chunkHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *chunkHeap) Push(x any) {
+	*h = append(*h, x.(chunkSpan))
+}
+
+func (h *chunkHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// arenaPool hands out chunkSize buffers for ranged downloads and lets
+// finished spans be returned for reuse. It is created once in
+// walkBucketObjects and shared by every object, so the extra memory ranged
+// downloads use is bounded by chunkSize * chunksPerObject * objectConcurrency
+// rather than growing with the number of objects downloaded.
+type arenaPool struct {
+	pool      sync.Pool
+	chunkSize int
+}
+
+func newArenaPool(chunkSize int) *arenaPool {
+	return &arenaPool{
+		chunkSize: chunkSize,
+		pool: sync.Pool{
+			New: func() any {
+				return make([]byte, chunkSize)
+			},
+		},
+	}
+}
+
+func (a *arenaPool) get() []byte {
+	return a.pool.Get().([]byte)
+}
+
+func (a *arenaPool) put(buf []byte) {
+	a.pool.Put(buf[:a.chunkSize])
+}
+
+// downloadObjectRanged splits an object into config.ChunkSize byte ranges,
+// fetches config.ChunksPerObject of them concurrently with minio's
+// SetRange, and streams the results into io.Discard in offset order using
+// a min-heap to hold chunks that complete early. Each chunk is recorded as
+// its own ChannelSample (marked chunk: true) in addition to the
+// whole-object sample downloadS3ObjectRanged pushes once this returns, so
+// summariseDownloads can report whole-object and per-chunk rates side by
+// side.
+func downloadObjectRanged(ctx context.Context, client *minio.Client, bucketName string, key string, objectSize int64, config *Config, arena *arenaPool, channel chan<- ChannelSample) (size uint64, statusCode int, firstStatusCode int, retries int) {
+	chunkSize := int64(config.ChunkSize)
+	tasks := make(chan chunkTask)
+	results := make(chan chunkSpan)
+
+	// slots caps the buffers this object holds (being fetched or waiting in
+	// the heap) at ChunksPerObject. A slot is taken before a task, so the
+	// lowest outstanding chunk always has one and reassembly never stalls.
+	slots := make(chan struct{}, config.ChunksPerObject)
+
+	var wg sync.WaitGroup
+	for range config.ChunksPerObject {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				slots <- struct{}{}
+				task, ok := <-tasks
+				if !ok {
+					<-slots
+					return
+				}
+				buf := arena.get()[:task.length]
+				start := time.Now()
+				opts := minio.GetObjectOptions{}
+				opts.SetRange(task.offset, task.offset+task.length-1)
+				var n int
+				firstStatus, finalStatus, retries := withRetry(ctx, config, func() (int, error) {
+					reader, err := client.GetObject(ctx, bucketName, key, opts)
+					if err == nil {
+						n, err = io.ReadFull(reader, buf)
+						reader.Close()
+					}
+					if err != nil {
+						n = 0
+						if status, ok := statusForContextErr(ctx); ok {
+							return status, ctx.Err()
+						}
+						resp := minio.ToErrorResponse(err)
+						log.Error("Failed to fetch chunk", "key", key, "offset", task.offset, "error", err)
+						return resp.StatusCode, err
+					}
+					return 200, nil
+				})
+				channel <- ChannelSample{size: uint64(n), objectName: key, statusCode: finalStatus, firstStatusCode: firstStatus, retries: retries, duration: time.Since(start), chunk: true, op: "GET"}
+				results <- chunkSpan{offset: task.offset, data: buf, n: n, statusCode: finalStatus, firstStatusCode: firstStatus, retries: retries}
+			}
+		}()
+	}
+
+	go func() {
+		for offset := int64(0); offset < objectSize; {
+			length := min(chunkSize, objectSize-offset)
+			tasks <- chunkTask{offset: offset, length: length}
+			offset += length
+		}
+		close(tasks)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Reorder spans by offset and stream them out as soon as the next
+	// expected offset is available, freeing each buffer back to the arena
+	// once it has been consumed. statusCode/firstStatusCode latch onto the
+	// first non-200 chunk outcome, so a single failed/retried-out chunk
+	// makes the whole-object sample reflect it instead of a hard-coded
+	// 200, and retries sums every chunk's retry count so the object-level
+	// sample feeding summariseDownloads' retry_summary isn't always zero.
+	pending := &chunkHeap{}
+	heap.Init(pending)
+	next := int64(0)
+	statusCode = 200
+	firstStatusCode = 200
+	for next < objectSize {
+		for pending.Len() == 0 || (*pending)[0].offset != next {
+			span, ok := <-results
+			if !ok {
+				return
+			}
+			heap.Push(pending, span)
+		}
+		span := heap.Pop(pending).(chunkSpan)
+		m, err := io.Discard.Write(span.data[:span.n])
+		if err != nil && statusCode == 200 {
+			statusCode = 500
+		}
+		if span.statusCode != 200 && statusCode == 200 {
+			statusCode = span.statusCode
+		}
+		if span.firstStatusCode != 200 && firstStatusCode == 200 {
+			firstStatusCode = span.firstStatusCode
+		}
+		retries += span.retries
+		size += uint64(m)
+		next += int64(len(span.data))
+		arena.put(span.data)
+		<-slots
+	}
+	return
+}
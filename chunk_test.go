@@ -0,0 +1,45 @@
+package main
+
+import (
+	"container/heap"
+	"testing"
+)
+
+func TestChunkHeapOrdersByOffset(t *testing.T) {
+	h := &chunkHeap{}
+	heap.Init(h)
+	offsets := []int64{300, 0, 600, 100, 200}
+	for _, offset := range offsets {
+		heap.Push(h, chunkSpan{offset: offset})
+	}
+
+	var got []int64
+	for h.Len() > 0 {
+		span := heap.Pop(h).(chunkSpan)
+		got = append(got, span.offset)
+	}
+
+	want := []int64{0, 100, 200, 300, 600}
+	if len(got) != len(want) {
+		t.Fatalf("got %d spans, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pop order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestArenaPoolReusesChunkSizedBuffers(t *testing.T) {
+	arena := newArenaPool(64)
+	buf := arena.get()
+	if len(buf) != 64 {
+		t.Fatalf("got buffer of length %d, want 64", len(buf))
+	}
+	arena.put(buf)
+
+	again := arena.get()
+	if len(again) != 64 {
+		t.Fatalf("got buffer of length %d after reuse, want 64", len(again))
+	}
+}
@@ -22,6 +22,54 @@ type Config struct {
 	LimitObjects  int
 	Quiet         bool
 	Debug         bool
+	// ChunkSize and ChunksPerObject switch on ranged, concurrent downloads
+	// per object, see downloadObjectRanged in chunk.go. ChunksPerObject <= 1
+	// keeps the original single-GET path.
+	ChunkSize       int
+	ChunksPerObject int
+	// MaxRetries, RetryBaseDelay and RetryMaxDelay control the full-jitter
+	// backoff retry wrapper in retry.go. Delays are in milliseconds.
+	MaxRetries     int
+	RetryBaseDelay int
+	RetryMaxDelay  int
+	// MetricsAddr, if set, serves Prometheus metrics (see metrics.go) on
+	// this address for the duration of the run (fetch, push or mixed),
+	// e.g. ":9090".
+	MetricsAddr string
+	// KeySource picks how object keys are produced, see objectKeys in
+	// workload.go: "list" lists the bucket (the original behaviour),
+	// "generated" synthesises bench/{shard}/{seq} keys without waiting on
+	// a listing, "file" reads newline-delimited keys from KeyFile.
+	KeySource string
+	KeyFile   string
+	// KeyShards is the number of hash-prefixed shards generated keys are
+	// spread across, to avoid hammering a single S3 partition.
+	KeyShards int
+	// ObjectSizeDist picks the synthetic object size distribution used by
+	// the push workload: "fixed", "uniform" or "lognormal", see
+	// objectSize in workload.go.
+	ObjectSizeDist string
+	// ObjectSizeMin is the fixed size for "fixed" and the lower bound for
+	// "uniform". ObjectSizeMax is the upper bound for "uniform".
+	ObjectSizeMin int64
+	ObjectSizeMax int64
+	// ObjectSizeMean and ObjectSizeSigma parameterise the "lognormal"
+	// distribution (mean and sigma of the underlying normal, in natural
+	// log of bytes).
+	ObjectSizeMean  float64
+	ObjectSizeSigma float64
+	// ReadWriteRatio is the fraction of requests that are GETs in the
+	// mixed workload; the remainder are PUTs.
+	ReadWriteRatio float64
+	// ResultsWebhookURL, if set, makes displayResults POST the result
+	// JSON to this endpoint, see webhook.go. ResultsWebhookAuthHeader
+	// defaults to "Authorization" and carries "Bearer <token>" when a
+	// token is set. ResultsWebhookExtraLabels is a comma-separated
+	// key=val list merged into the JSON top level.
+	ResultsWebhookURL         string
+	ResultsWebhookAuthToken   string
+	ResultsWebhookAuthHeader  string
+	ResultsWebhookExtraLabels string
 }
 
 var ARG_DEBUG string = "debug"
@@ -33,6 +81,25 @@ var ARG_REGION string = "region"
 var ARG_ENDPOINT string = "endpoint"
 var ARG_LIMIT_DURATION string = "limit-duration"
 var ARG_LIMIT_OBJECTS string = "limit-objects"
+var ARG_CHUNK_SIZE string = "chunk-size"
+var ARG_CHUNKS_PER_OBJECT string = "chunks-per-object"
+var ARG_MAX_RETRIES string = "max-retries"
+var ARG_RETRY_BASE_DELAY string = "retry-base-delay"
+var ARG_RETRY_MAX_DELAY string = "retry-max-delay"
+var ARG_METRICS_ADDR string = "metrics-addr"
+var ARG_KEY_SOURCE string = "key-source"
+var ARG_KEY_FILE string = "key-file"
+var ARG_KEY_SHARDS string = "key-shards"
+var ARG_OBJECT_SIZE_DIST string = "object-size-dist"
+var ARG_OBJECT_SIZE_MIN string = "object-size-min"
+var ARG_OBJECT_SIZE_MAX string = "object-size-max"
+var ARG_OBJECT_SIZE_MEAN string = "object-size-mean"
+var ARG_OBJECT_SIZE_SIGMA string = "object-size-sigma"
+var ARG_READ_WRITE_RATIO string = "read-write-ratio"
+var ARG_RESULTS_WEBHOOK string = "results-webhook"
+var ARG_RESULTS_WEBHOOK_AUTH_TOKEN string = "results-webhook-auth-token"
+var ARG_RESULTS_WEBHOOK_AUTH_HEADER string = "results-webhook-auth-header"
+var ARG_RESULTS_WEBHOOK_EXTRA_LABELS string = "results-webhook-extra-labels"
 
 // Parse the given INI configuration file.
 // It is expected that the file is in rclone format and contains a section with the given name.
@@ -86,6 +153,19 @@ func setConfigInt(config *Config, cmd *cli.Command, field string, name string) {
 	}
 }
 
+// A utility function to set a float64 field in the Config struct.
+func setConfigFloat(config *Config, cmd *cli.Command, field string, name string) {
+	value := cmd.Float(name)
+	if value < 0 {
+		return
+	}
+	r := reflect.ValueOf(config)
+	f := reflect.Indirect(r).FieldByName(field)
+	if f.Kind() != reflect.Invalid {
+		f.SetFloat(value)
+	}
+}
+
 // Returns the Config struct used by the rest of the program
 // Command line args are applied, e.g. to load config file.
 func getConfig(cmd *cli.Command) *Config {
@@ -115,6 +195,44 @@ func getConfig(cmd *cli.Command) *Config {
 	setConfigString(&config, cmd, "S3BucketName", ARG_BUCKET_NAME)
 	setConfigInt(&config, cmd, "LimitDuration", ARG_LIMIT_DURATION)
 	setConfigInt(&config, cmd, "LimitObjects", ARG_LIMIT_OBJECTS)
+	setConfigInt(&config, cmd, "ChunkSize", ARG_CHUNK_SIZE)
+	setConfigInt(&config, cmd, "ChunksPerObject", ARG_CHUNKS_PER_OBJECT)
+	setConfigInt(&config, cmd, "MaxRetries", ARG_MAX_RETRIES)
+	setConfigInt(&config, cmd, "RetryBaseDelay", ARG_RETRY_BASE_DELAY)
+	setConfigInt(&config, cmd, "RetryMaxDelay", ARG_RETRY_MAX_DELAY)
+	setConfigString(&config, cmd, "MetricsAddr", ARG_METRICS_ADDR)
+	setConfigString(&config, cmd, "KeySource", ARG_KEY_SOURCE)
+	setConfigString(&config, cmd, "KeyFile", ARG_KEY_FILE)
+	setConfigInt(&config, cmd, "KeyShards", ARG_KEY_SHARDS)
+	setConfigString(&config, cmd, "ObjectSizeDist", ARG_OBJECT_SIZE_DIST)
+	setConfigInt(&config, cmd, "ObjectSizeMin", ARG_OBJECT_SIZE_MIN)
+	setConfigInt(&config, cmd, "ObjectSizeMax", ARG_OBJECT_SIZE_MAX)
+	setConfigFloat(&config, cmd, "ObjectSizeMean", ARG_OBJECT_SIZE_MEAN)
+	setConfigFloat(&config, cmd, "ObjectSizeSigma", ARG_OBJECT_SIZE_SIGMA)
+	setConfigFloat(&config, cmd, "ReadWriteRatio", ARG_READ_WRITE_RATIO)
+	setConfigString(&config, cmd, "ResultsWebhookURL", ARG_RESULTS_WEBHOOK)
+	setConfigString(&config, cmd, "ResultsWebhookAuthToken", ARG_RESULTS_WEBHOOK_AUTH_TOKEN)
+	setConfigString(&config, cmd, "ResultsWebhookAuthHeader", ARG_RESULTS_WEBHOOK_AUTH_HEADER)
+	setConfigString(&config, cmd, "ResultsWebhookExtraLabels", ARG_RESULTS_WEBHOOK_EXTRA_LABELS)
+
+	if config.KeySource == "" {
+		config.KeySource = "list"
+	}
+	if config.ObjectSizeDist == "" {
+		config.ObjectSizeDist = "fixed"
+	}
+	if config.KeyShards < 1 {
+		config.KeyShards = 16
+	}
+	if config.ReadWriteRatio <= 0 {
+		config.ReadWriteRatio = 0.5
+	}
+	if config.ResultsWebhookAuthHeader == "" {
+		config.ResultsWebhookAuthHeader = "Authorization"
+	}
+	if config.ChunksPerObject > 1 && config.ChunkSize <= 0 {
+		log.Fatal("--chunk-size must be set to a positive value when --chunks-per-object > 1")
+	}
 
 	if cmd.Bool(ARG_DEBUG) {
 		config.Debug = true
@@ -10,16 +10,17 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"reflect"
 	"slices"
 	"sort"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/dustin/go-humanize"
 	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/urfave/cli/v3"
 	"gonum.org/v1/gonum/stat"
 )
@@ -29,6 +30,16 @@ type ChannelSample struct {
 	objectName string
 	statusCode int
 	duration   time.Duration
+	// chunk is true for a sample representing a single ranged chunk of an
+	// object rather than the object as a whole, see downloadObjectRanged.
+	chunk bool
+	// firstStatusCode is the status of the first attempt, before any
+	// retries from retry.go; statusCode holds the final attempt's status.
+	firstStatusCode int
+	retries         int
+	// op is "GET" or "PUT"; see downloadS3Object and uploadS3Object in
+	// workload.go.
+	op string
 }
 
 type Result struct {
@@ -51,37 +62,93 @@ type StatsSummary struct {
 }
 
 // Download one Object from S3, this function is called within a goroutine.
-func downloadS3Object(client *minio.Client, ctx context.Context, bucketName string, key string, channel chan<- ChannelSample) {
-	statusCode := 200
+// When config.ChunksPerObject is greater than 1 the object is split into
+// byte ranges and fetched concurrently via downloadObjectRanged, otherwise
+// it falls back to a single whole-object GET.
+func downloadS3Object(client *minio.Client, ctx context.Context, config *Config, arena *arenaPool, bucketName string, key string, channel chan<- ChannelSample) {
+	if config.ChunksPerObject > 1 {
+		downloadS3ObjectRanged(client, ctx, config, arena, bucketName, key, channel)
+		return
+	}
+
 	start := time.Now()
-	reader, err := client.GetObject(ctx, bucketName, key, minio.GetObjectOptions{})
-	if err != nil {
-		resp := minio.ToErrorResponse(err)
-		log.Error(resp.Message, "response", resp)
-		log.Fatal(err)
+	var size uint64
+	// withRetry wraps both the GetObject call and the io.Copy drain, since
+	// a transient error can surface from either one.
+	firstStatus, finalStatus, retries := withRetry(ctx, config, func() (int, error) {
+		reader, err := client.GetObject(ctx, bucketName, key, minio.GetObjectOptions{})
+		if err != nil {
+			if status, ok := statusForContextErr(ctx); ok {
+				return status, ctx.Err()
+			}
+			resp := minio.ToErrorResponse(err)
+			log.Error(resp.Message, "response", resp)
+			return resp.StatusCode, err
+		}
+		defer reader.Close()
+		// Save result to io.Discard this is like writing to /dev/null
+		// it is convenient to get the size of the object and simulate a write
+		// but not be bound by the disk speed.
+		isize, err := io.Copy(io.Discard, reader)
+		if err != nil {
+			if status, ok := statusForContextErr(ctx); ok {
+				return status, ctx.Err()
+			}
+			resp := minio.ToErrorResponse(err)
+			log.Error(fmt.Sprintf("Failed to read %s:", key), "error", err)
+			msg := fmt.Sprintf("%s (%d) HostID: %s RequestID: %s", resp.Code, resp.StatusCode, resp.HostID, resp.RequestID)
+			log.Debug("Failed to read", err, msg)
+			return resp.StatusCode, err
+		}
+		// Rather use resp.Code:str that resp.StatusCode:int for the response.
+		// resp.StatusCode is an integer representing the HTTP status code of the response
+		// thus it is only use full for HTTP errors, we do see other issues.
+		size = uint64(isize)
+		return 200, nil
+	})
+	if retries > 0 {
+		log.Info("Recovered after retries", "key", key, "retries", retries, "status", finalStatus)
 	}
-	defer reader.Close()
-	// Save result to io.Discard this is like writing to /dev/null
-	// it is convenient to get the size of the object and simulate a write
-	// but not be bound by the disk speed.
-	isize, err := io.Copy(io.Discard, reader)
-	if err != nil {
-		resp := minio.ToErrorResponse(err)
-		log.Error(fmt.Sprintf("Failed to read %s:", key), "error", err)
-		msg := fmt.Sprintf("%s (%d) HostID: %s RequestID: %s", resp.Code, resp.StatusCode, resp.HostID, resp.RequestID)
-		log.Debug("Failed to read", err, msg)
-		statusCode = resp.StatusCode
+	channel <- ChannelSample{size: size, objectName: key, statusCode: finalStatus, firstStatusCode: firstStatus, retries: retries, duration: time.Since(start), op: "GET"}
+}
+
+// downloadS3Object is responsible for the whole-object sample it pushes to
+// channel; downloadS3ObjectRanged is its counterpart for the ranged path,
+// see chunk.go for the chunk scheduling and arena reuse.
+func downloadS3ObjectRanged(client *minio.Client, ctx context.Context, config *Config, arena *arenaPool, bucketName string, key string, channel chan<- ChannelSample) {
+	start := time.Now()
+	var info minio.ObjectInfo
+	// A transient error sizing the object (503, SlowDown) shouldn't take
+	// down the whole benchmark run, so this goes through withRetry and
+	// reports a failed sample instead of log.Fatal.
+	firstStatStatus, finalStatStatus, statRetries := withRetry(ctx, config, func() (int, error) {
+		var err error
+		info, err = client.StatObject(ctx, bucketName, key, minio.StatObjectOptions{})
+		if err != nil {
+			if status, ok := statusForContextErr(ctx); ok {
+				return status, ctx.Err()
+			}
+			resp := minio.ToErrorResponse(err)
+			log.Error(resp.Message, "response", resp)
+			return resp.StatusCode, err
+		}
+		return 200, nil
+	})
+	if finalStatStatus != 200 {
+		channel <- ChannelSample{objectName: key, statusCode: finalStatStatus, firstStatusCode: firstStatStatus, retries: statRetries, duration: time.Since(start), op: "GET"}
+		return
 	}
 
-	// Rather use resp.Code:str that resp.StatusCode:int for the response.
-	// resp.StatusCode is an integer representing the HTTP status code of the response
-	// thus it is only use full for HTTP errors, we do see other issues.
-	size := uint64(isize)
-	channel <- ChannelSample{size: size, objectName: key, statusCode: statusCode, duration: time.Since(start)}
+	size, statusCode, firstStatusCode, retries := downloadObjectRanged(ctx, client, bucketName, key, info.Size, config, arena, channel)
+	if retries > 0 {
+		log.Info("Recovered after retries", "key", key, "retries", retries, "status", statusCode)
+	}
+	channel <- ChannelSample{size: size, objectName: key, statusCode: statusCode, firstStatusCode: firstStatusCode, retries: retries, duration: time.Since(start), op: "GET"}
 }
 
 // Listen on the Sample channel and collect all the performance stats from the goroutines doing the downloads.
-func collectResult(channel chan ChannelSample, task_counter *int, result *Result) {
+// metrics may be nil, in which case no Prometheus collectors are updated.
+func collectResult(channel chan ChannelSample, task_counter *int, result *Result, metrics *Metrics) {
 	count := 0
 	var total_size uint64 = 0
 	total_duration := 0.0
@@ -94,6 +161,9 @@ func collectResult(channel chan ChannelSample, task_counter *int, result *Result
 		total_size += sample.size
 		total_duration = time.Since(startTime).Seconds()
 		rate = float64(total_size) / total_duration
+		if metrics != nil {
+			metrics.observe(sample, *task_counter)
+		}
 		if time.Since(lastPrint) > 2*time.Second {
 			msg := fmt.Sprintf("Tasks ~%d. Downloaded %d objects: Total %s in %v.",
 				*task_counter,
@@ -118,55 +188,106 @@ func collectResult(channel chan ChannelSample, task_counter *int, result *Result
 	result.rate = rate
 }
 
+// RetrySummary reports how much the retry wrapper in retry.go had to do
+// across all whole-object samples in a run.
+type RetrySummary struct {
+	SamplesRetried int `json:"samples_retried"`
+	TotalRetries   int `json:"total_retries"`
+	MaxRetries     int `json:"max_retries"`
+}
+
 // Create a summary of the downloads.
-func summariseDownloads(result *Result) (StatsSummary, StatsSummary, map[int]int) {
+// Samples are split on the chunk flag: whole-object samples feed size_stats
+// and rate_stats as before, ranged chunk samples (see chunk.go) feed the
+// additional chunk_rate_stats so both views are available side by side.
+// status_map reports final-attempt status codes, first_status_map reports
+// the status of the first attempt, so users can see whether the store is
+// degraded even when retries quietly recovered the run.
+func summariseDownloads(result *Result) (StatsSummary, StatsSummary, *StatsSummary, RetrySummary, map[int]int, map[int]int) {
 	var samples_rate []float64
 	var samples_size []float64
+	var chunk_samples_rate []float64
 	status_map := make(map[int]int)
+	first_status_map := make(map[int]int)
+	retry_summary := RetrySummary{}
 	for _, sample := range result.samples {
+		if sample.chunk {
+			chunk_samples_rate = append(chunk_samples_rate, float64(sample.size)/sample.duration.Seconds())
+			continue
+		}
 		samples_size = append(samples_size, float64(sample.size))
 		sample_rate := float64(sample.size) / sample.duration.Seconds()
 		samples_rate = append(samples_rate, sample_rate)
 		status_map[sample.statusCode]++
+		first_status_map[sample.firstStatusCode]++
+		if sample.retries > 0 {
+			retry_summary.SamplesRetried++
+			retry_summary.TotalRetries += sample.retries
+			retry_summary.MaxRetries = max(retry_summary.MaxRetries, sample.retries)
+		}
+	}
+
+	size_stats := StatsSummary{Unit: "B"}
+	if len(samples_size) > 0 {
+		sort.Float64s(samples_size)
+		log.Debug("object size", "max", humanize.Bytes(uint64(slices.Max(samples_size))))
+		log.Debug("object size", "mean", humanize.Bytes(uint64(stat.Mean(samples_size, nil))))
+		log.Debug("object size", "P99", humanize.Bytes(uint64(stat.Quantile(0.99, stat.Empirical, samples_size, nil))))
+		log.Debug("object size", "P95", humanize.Bytes(uint64(stat.Quantile(0.95, stat.Empirical, samples_size, nil))))
+		log.Debug("object size", "P90", humanize.Bytes(uint64(stat.Quantile(0.90, stat.Empirical, samples_size, nil))))
+		log.Debug("object size", "P50", humanize.Bytes(uint64(stat.Quantile(0.50, stat.Empirical, samples_size, nil))))
+
+		size_stats = StatsSummary{
+			Max:  slices.Max(samples_size),
+			Mean: stat.Mean(samples_size, nil),
+			P99:  stat.Quantile(0.99, stat.Empirical, samples_size, nil),
+			P95:  stat.Quantile(0.95, stat.Empirical, samples_size, nil),
+			P90:  stat.Quantile(0.90, stat.Empirical, samples_size, nil),
+			P50:  stat.Quantile(0.50, stat.Empirical, samples_size, nil),
+			Unit: "B",
+		}
 	}
 
-	sort.Float64s(samples_size)
-	log.Debug("object size", "max", humanize.Bytes(uint64(slices.Max(samples_size))))
-	log.Debug("object size", "mean", humanize.Bytes(uint64(stat.Mean(samples_size, nil))))
-	log.Debug("object size", "P99", humanize.Bytes(uint64(stat.Quantile(0.99, stat.Empirical, samples_size, nil))))
-	log.Debug("object size", "P95", humanize.Bytes(uint64(stat.Quantile(0.95, stat.Empirical, samples_size, nil))))
-	log.Debug("object size", "P90", humanize.Bytes(uint64(stat.Quantile(0.90, stat.Empirical, samples_size, nil))))
-	log.Debug("object size", "P50", humanize.Bytes(uint64(stat.Quantile(0.50, stat.Empirical, samples_size, nil))))
-
-	size_stats := StatsSummary{
-		Max:  slices.Max(samples_size),
-		Mean: stat.Mean(samples_size, nil),
-		P99:  stat.Quantile(0.99, stat.Empirical, samples_size, nil),
-		P95:  stat.Quantile(0.95, stat.Empirical, samples_size, nil),
-		P90:  stat.Quantile(0.90, stat.Empirical, samples_size, nil),
-		P50:  stat.Quantile(0.50, stat.Empirical, samples_size, nil),
-		Unit: "B",
+	rate_stats := StatsSummary{Unit: "B/s"}
+	if len(samples_rate) > 0 {
+		sort.Float64s(samples_rate)
+		log.Debug("per object download rate", "max", humanize.Bytes(uint64(slices.Max(samples_rate))))
+		log.Debug("per object download rate", "mean", humanize.Bytes(uint64(stat.Mean(samples_rate, nil))))
+		log.Debug("per object download rate", "P99", humanize.Bytes(uint64(stat.Quantile(0.99, stat.Empirical, samples_rate, nil))))
+		log.Debug("per object download rate", "P95", humanize.Bytes(uint64(stat.Quantile(0.95, stat.Empirical, samples_rate, nil))))
+		log.Debug("per object download rate", "P90", humanize.Bytes(uint64(stat.Quantile(0.90, stat.Empirical, samples_rate, nil))))
+		log.Debug("per object download rate", "P50", humanize.Bytes(uint64(stat.Quantile(0.50, stat.Empirical, samples_rate, nil))))
+
+		rate_stats = StatsSummary{
+			Max:  slices.Max(samples_rate),
+			Mean: stat.Mean(samples_rate, nil),
+			P99:  stat.Quantile(0.99, stat.Empirical, samples_rate, nil),
+			P95:  stat.Quantile(0.95, stat.Empirical, samples_rate, nil),
+			P90:  stat.Quantile(0.90, stat.Empirical, samples_rate, nil),
+			P50:  stat.Quantile(0.50, stat.Empirical, samples_rate, nil),
+			Unit: "B/s",
+		}
 	}
 
-	sort.Float64s(samples_rate)
-	log.Debug("per object download rate", "max", humanize.Bytes(uint64(slices.Max(samples_rate))))
-	log.Debug("per object download rate", "mean", humanize.Bytes(uint64(stat.Mean(samples_rate, nil))))
-	log.Debug("per object download rate", "P99", humanize.Bytes(uint64(stat.Quantile(0.99, stat.Empirical, samples_rate, nil))))
-	log.Debug("per object download rate", "P95", humanize.Bytes(uint64(stat.Quantile(0.95, stat.Empirical, samples_rate, nil))))
-	log.Debug("per object download rate", "P90", humanize.Bytes(uint64(stat.Quantile(0.90, stat.Empirical, samples_rate, nil))))
-	log.Debug("per object download rate", "P50", humanize.Bytes(uint64(stat.Quantile(0.50, stat.Empirical, samples_rate, nil))))
-
-	rate_stats := StatsSummary{
-		Max:  slices.Max(samples_rate),
-		Mean: stat.Mean(samples_rate, nil),
-		P99:  stat.Quantile(0.99, stat.Empirical, samples_rate, nil),
-		P95:  stat.Quantile(0.95, stat.Empirical, samples_rate, nil),
-		P90:  stat.Quantile(0.90, stat.Empirical, samples_rate, nil),
-		P50:  stat.Quantile(0.50, stat.Empirical, samples_rate, nil),
-		Unit: "B/s",
+	// chunk_rate_stats is only present for ranged downloads (ChunksPerObject
+	// > 1); a pointer lets the common non-ranged case omit it from the JSON
+	// output entirely instead of marshalling an all-zero struct (omitempty
+	// has no effect on a non-pointer struct field).
+	var chunk_rate_stats *StatsSummary
+	if len(chunk_samples_rate) > 0 {
+		sort.Float64s(chunk_samples_rate)
+		chunk_rate_stats = &StatsSummary{
+			Max:  slices.Max(chunk_samples_rate),
+			Mean: stat.Mean(chunk_samples_rate, nil),
+			P99:  stat.Quantile(0.99, stat.Empirical, chunk_samples_rate, nil),
+			P95:  stat.Quantile(0.95, stat.Empirical, chunk_samples_rate, nil),
+			P90:  stat.Quantile(0.90, stat.Empirical, chunk_samples_rate, nil),
+			P50:  stat.Quantile(0.50, stat.Empirical, chunk_samples_rate, nil),
+			Unit: "B/s",
+		}
 	}
 
-	return size_stats, rate_stats, status_map
+	return size_stats, rate_stats, chunk_rate_stats, retry_summary, status_map, first_status_map
 }
 
 // After all the downloads are completed, present the results.
@@ -180,7 +301,7 @@ func displayResults(result *Result, config *Config) {
 
 	log.Print(msg)
 
-	size_stats, rate_stats, status_map := summariseDownloads(result)
+	size_stats, rate_stats, chunk_rate_stats, retry_summary, status_map, first_status_map := summariseDownloads(result)
 
 	Hostname, err := os.Hostname()
 	if err != nil {
@@ -189,30 +310,36 @@ func displayResults(result *Result, config *Config) {
 
 	// Initialize the Response struct to make the JSON output more readable
 	type Response struct {
-		Host               string       `json:"host"`
-		S3host             string       `json:"s3host"`
-		Https              bool         `json:"https"`
-		Start_time         int64        `json:"start_time"`
-		End_time           int64        `json:"end_time"`
-		Bucket_name        string       `json:"bucket_name"`
-		Downloaded_bytes   uint64       `json:"downloaded_bytes"`
-		Downloaded_objects uint64       `json:"downloaded_objects"`
-		Rate_stats         StatsSummary `json:"rate_stats"`
-		Size_stats         StatsSummary `json:"size_stats"`
-		Http_status        map[int]int  `json:"http_status"`
+		Host                string        `json:"host"`
+		S3host              string        `json:"s3host"`
+		Https               bool          `json:"https"`
+		Start_time          int64         `json:"start_time"`
+		End_time            int64         `json:"end_time"`
+		Bucket_name         string        `json:"bucket_name"`
+		Downloaded_bytes    uint64        `json:"downloaded_bytes"`
+		Downloaded_objects  uint64        `json:"downloaded_objects"`
+		Rate_stats          StatsSummary  `json:"rate_stats"`
+		Size_stats          StatsSummary  `json:"size_stats"`
+		Chunk_rate_stats    *StatsSummary `json:"chunk_rate_stats,omitempty"`
+		Retry_summary       RetrySummary  `json:"retry_summary"`
+		Http_status         map[int]int   `json:"http_status"`
+		Http_status_initial map[int]int   `json:"http_status_initial"`
 	}
 	response := Response{
-		Host:               Hostname,
-		S3host:             config.S3Host,
-		Https:              config.UseSSL,
-		Start_time:         result.start.Unix(),
-		End_time:           result.end.Unix(),
-		Bucket_name:        config.S3BucketName,
-		Downloaded_bytes:   result.size,
-		Downloaded_objects: uint64(result.objects),
-		Rate_stats:         rate_stats,
-		Size_stats:         size_stats,
-		Http_status:        status_map,
+		Host:                Hostname,
+		S3host:              config.S3Host,
+		Https:               config.UseSSL,
+		Start_time:          result.start.Unix(),
+		End_time:            result.end.Unix(),
+		Bucket_name:         config.S3BucketName,
+		Downloaded_bytes:    result.size,
+		Downloaded_objects:  uint64(result.objects),
+		Rate_stats:          rate_stats,
+		Size_stats:          size_stats,
+		Chunk_rate_stats:    chunk_rate_stats,
+		Retry_summary:       retry_summary,
+		Http_status:         status_map,
+		Http_status_initial: first_status_map,
 	}
 	data, err := json.Marshal(response)
 	if err != nil {
@@ -220,15 +347,14 @@ func displayResults(result *Result, config *Config) {
 	}
 	fmt.Println()
 	fmt.Println(string(data))
-}
 
-// Do a bucket listing (list all objects in the bucket) and start a download for each object.
-// This function is where we launch the goroutines to download the objects from and the
-// collectResults goroutine.
-func walkBucketObjects(config *Config, samples chan<- ChannelSample, counter *int) {
+	postResult(config, data)
+}
 
-	ctx := context.TODO()
-	tr := &http.Transport{
+// newTransport builds the tuned HTTP transport shared by every workload
+// mode (fetch, push, mixed; see workload.go).
+func newTransport() *http.Transport {
+	return &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
 			Timeout:   60 * time.Second,
@@ -246,66 +372,109 @@ func walkBucketObjects(config *Config, samples chan<- ChannelSample, counter *in
 		//    https://golang.org/src/net/http/transport.go?h=roundTrip#L1843
 		DisableCompression: true,
 	}
-	s3Client, err := minio.New(config.S3Host,
-		&minio.Options{
-			Creds:     credentials.NewStaticV4(config.S3AccessKey, config.S3SecretKey, ""),
-			Secure:    config.UseSSL,
-			Transport: tr,
-		})
+}
+
+// dispatchKeys launches one goroutine per key pulled from keys, applying
+// the same LimitObjects bookkeeping walkBucketObjects has always used, plus
+// ctx cancellation: ctx is expected to already carry a deadline derived
+// from LimitDuration (see workloadContext) and to be cancelled by the
+// caller on SIGINT/SIGTERM, so a single ctx.Done() check here covers both.
+// cancel is called as soon as LimitObjects is reached so objectKeys' key
+// producer (see workload.go), which selects on the same ctx, stops blocking
+// on a send nobody is going to read anymore. It is shared by the fetch,
+// push and mixed workloads (see workload.go) so the limit handling only
+// lives in one place.
+func dispatchKeys(ctx context.Context, cancel context.CancelFunc, config *Config, keys <-chan string, counter *int, perform func(ctx context.Context, key string)) {
+	var wg sync.WaitGroup
+	max_counter := 0
+loop:
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok {
+				break loop
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				*counter++ // Counter is updated from multiple goroutines with no Locks or synchronization, expect inconsistency.
+				perform(ctx, key)
+				*counter--
+			}()
+			max_counter++
+			if config.LimitObjects > 0 && max_counter >= config.LimitObjects {
+				log.Warn("Max objects reached. Please wait for in-flight requests to finish.")
+				cancel()
+				break loop
+			}
+		case <-ctx.Done():
+			log.Warn("Reached the limit duration, or interrupted. Please wait for queued requests to complete.")
+			break loop
+		}
+	}
+
+	log.Warn("Please wait for in-flight requests to complete.")
+	wg.Wait()
+	log.Info("Done.")
+}
+
+// workloadContext derives a cancellable context for a single walk call from
+// the run's top-level context (which main cancels on SIGINT/SIGTERM), also
+// applying config.LimitDuration as a deadline if set. The returned cancel
+// must be deferred by the caller so in-flight requests still running when
+// the walk returns are freed promptly rather than at process exit.
+func workloadContext(ctx context.Context, config *Config) (context.Context, context.CancelFunc) {
+	if config.LimitDuration > 0 {
+		return context.WithTimeout(ctx, time.Duration(config.LimitDuration)*time.Second)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Do a bucket listing (list all objects in the bucket) and start a download for each object.
+// This function is where we launch the goroutines to download the objects from and the
+// collectResults goroutine.
+func walkBucketObjects(ctx context.Context, config *Config, samples chan<- ChannelSample, counter *int) {
+	ctx, cancel := workloadContext(ctx, config)
+	defer cancel()
+
+	s3Client, err := newS3Client(config)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
-	opts := minio.ListObjectsOptions{
-		Recursive:       true,
-		ReverseVersions: false,
-		WithVersions:    false,
-		WithMetadata:    false,
-		MaxKeys:         3000, // <1000 Causes more fetches but reduces memory usage.
+	keys, err := objectKeys(ctx, config, s3Client)
+	if err != nil {
+		log.Error("Failed to set up key source", "error", err)
+		return
 	}
 
-	var wg sync.WaitGroup
-	// List all objects in a bucket.
-	max_counter := 0
-	maxSecondsStart := time.Now()
-	for object := range s3Client.ListObjects(ctx, config.S3BucketName, opts) {
-		if object.Err != nil {
-			log.Error(object.Err)
-			return
-		}
-
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			*counter++ // Counter is updated from multiple goroutines with no Locks or synchronization, expect inconsistency.
-			if config.LimitDuration > 0 && time.Since(maxSecondsStart).Seconds() >= float64(config.LimitDuration) {
-				log.Warn("Reached the limit duration. Clearing the queue.")
-			} else {
-				downloadS3Object(s3Client, ctx, config.S3BucketName, object.Key, samples)
-			}
-			*counter--
-		}()
-		max_counter++
-		if config.LimitObjects > 0 && max_counter >= config.LimitObjects {
-			log.Warn("Max objects to download reached. Please wait for the objects to be downloaded.")
-			break
-		}
-		if config.LimitDuration > 0 && time.Since(maxSecondsStart).Seconds() >= float64(config.LimitDuration) {
-			// Need a more forced way to stop the downloads in progress.
-			log.Warn("Reached the limit duration. Please wait for queued downloads to complete.")
-			break
-		}
+	// Shared across every object download so ranged chunk buffers are reused
+	// instead of being allocated per object; bounds the extra memory ranged
+	// downloads add to chunkSize * chunksPerObject * objectConcurrency.
+	var arena *arenaPool
+	if config.ChunksPerObject > 1 {
+		arena = newArenaPool(config.ChunkSize)
 	}
 
-	log.Warn("Please wait for download to complete.")
-	wg.Wait()
-	log.Info("Downloads done.")
+	dispatchKeys(ctx, cancel, config, keys, counter, func(ctx context.Context, key string) {
+		downloadS3Object(s3Client, ctx, config, arena, config.S3BucketName, key, samples)
+	})
 }
 
-// entry point for the fetch subcommand
-func cmd_run(ctx context.Context, cmd *cli.Command) error {
-	config := getConfig(cmd)
+// runWorkload wires up the metrics server, sample channel and
+// collectResult goroutine shared by every workload mode, hands the ready
+// channel and counter to walk (one of walkBucketObjects, walkPushObjects,
+// walkMixedObjects), then prints the results once it returns. ctx is the
+// run's top-level context; displayResults still runs even if ctx was
+// cancelled mid-run, so Ctrl-C flushes whatever samples were collected.
+func runWorkload(ctx context.Context, config *Config, walk func(ctx context.Context, config *Config, samples chan<- ChannelSample, counter *int)) error {
+	var metrics *Metrics
+	if config.MetricsAddr != "" {
+		metrics = newMetrics()
+		metricsServer := startMetricsServer(config.MetricsAddr)
+		defer metricsServer.Close()
+	}
 
 	result := Result{}
 	samples := make(chan ChannelSample)
@@ -314,10 +483,9 @@ func cmd_run(ctx context.Context, cmd *cli.Command) error {
 	wg_ctr.Add(1)
 	go func() {
 		defer wg_ctr.Done()
-		collectResult(samples, &shared_counter, &result)
+		collectResult(samples, &shared_counter, &result, metrics)
 	}()
-	// Iterate through the objects and download each one asynchronously
-	walkBucketObjects(config, samples, &shared_counter)
+	walk(ctx, config, samples, &shared_counter)
 	close(samples)
 	log.Debug("Stopping.")
 	wg_ctr.Wait()
@@ -325,6 +493,21 @@ func cmd_run(ctx context.Context, cmd *cli.Command) error {
 	return nil
 }
 
+// entry point for the fetch subcommand
+func cmd_run(ctx context.Context, cmd *cli.Command) error {
+	return runWorkload(ctx, getConfig(cmd), walkBucketObjects)
+}
+
+// entry point for the push subcommand
+func cmd_push(ctx context.Context, cmd *cli.Command) error {
+	return runWorkload(ctx, getConfig(cmd), walkPushObjects)
+}
+
+// entry point for the mixed subcommand
+func cmd_mixed(ctx context.Context, cmd *cli.Command) error {
+	return runWorkload(ctx, getConfig(cmd), walkMixedObjects)
+}
+
 // entry point for the show subcommand
 func cmd_show(ctx context.Context, cmd *cli.Command) error {
 	config := getConfig(cmd)
@@ -390,6 +573,101 @@ func main() {
 				Value: -1,
 				Usage: "Download this many objects",
 			},
+			&cli.IntFlag{
+				Name:  ARG_CHUNK_SIZE,
+				Value: -1,
+				Usage: "Size in bytes of each ranged chunk when chunks-per-object > 1",
+			},
+			&cli.IntFlag{
+				Name:  ARG_CHUNKS_PER_OBJECT,
+				Value: -1,
+				Usage: "Download each object as this many concurrent byte-range requests instead of a single GET",
+			},
+			&cli.IntFlag{
+				Name:  ARG_MAX_RETRIES,
+				Value: -1,
+				Usage: "Retry a request this many times on transient errors (network timeouts, 5xx, SlowDown) before giving up",
+			},
+			&cli.IntFlag{
+				Name:  ARG_RETRY_BASE_DELAY,
+				Value: -1,
+				Usage: "Base delay in milliseconds for full-jitter exponential backoff between retries",
+			},
+			&cli.IntFlag{
+				Name:  ARG_RETRY_MAX_DELAY,
+				Value: -1,
+				Usage: "Maximum delay in milliseconds for full-jitter exponential backoff between retries",
+			},
+			&cli.StringFlag{
+				Name:  ARG_METRICS_ADDR,
+				Value: "",
+				Usage: "Serve Prometheus metrics on this address during the run, e.g. :9090",
+			},
+			&cli.StringFlag{
+				Name:  ARG_KEY_SOURCE,
+				Value: "list",
+				Usage: "Where object keys come from: list (ListObjects), generated (bench/{shard}/{seq}), or file (see key-file)",
+			},
+			&cli.StringFlag{
+				Name:  ARG_KEY_FILE,
+				Value: "",
+				Usage: "Path to a newline-delimited file of keys, used when key-source=file",
+			},
+			&cli.IntFlag{
+				Name:  ARG_KEY_SHARDS,
+				Value: 16,
+				Usage: "Number of hash-prefixed shards generated keys are spread across",
+			},
+			&cli.StringFlag{
+				Name:  ARG_OBJECT_SIZE_DIST,
+				Value: "fixed",
+				Usage: "Synthetic object size distribution for push: fixed, uniform, or lognormal",
+			},
+			&cli.IntFlag{
+				Name:  ARG_OBJECT_SIZE_MIN,
+				Value: 1048576,
+				Usage: "Object size in bytes for object-size-dist=fixed, lower bound for uniform",
+			},
+			&cli.IntFlag{
+				Name:  ARG_OBJECT_SIZE_MAX,
+				Value: -1,
+				Usage: "Upper bound in bytes for object-size-dist=uniform",
+			},
+			&cli.FloatFlag{
+				Name:  ARG_OBJECT_SIZE_MEAN,
+				Value: -1,
+				Usage: "Mean (natural log of bytes) for object-size-dist=lognormal",
+			},
+			&cli.FloatFlag{
+				Name:  ARG_OBJECT_SIZE_SIGMA,
+				Value: -1,
+				Usage: "Sigma (natural log of bytes) for object-size-dist=lognormal",
+			},
+			&cli.FloatFlag{
+				Name:  ARG_READ_WRITE_RATIO,
+				Value: -1,
+				Usage: "Fraction of requests that are GETs in the mixed workload; the rest are PUTs",
+			},
+			&cli.StringFlag{
+				Name:  ARG_RESULTS_WEBHOOK,
+				Value: "",
+				Usage: "POST the result JSON to this URL once the run completes",
+			},
+			&cli.StringFlag{
+				Name:  ARG_RESULTS_WEBHOOK_AUTH_TOKEN,
+				Value: "",
+				Usage: "Bearer token sent with the results webhook request",
+			},
+			&cli.StringFlag{
+				Name:  ARG_RESULTS_WEBHOOK_AUTH_HEADER,
+				Value: "Authorization",
+				Usage: "Header used to carry the results webhook auth token",
+			},
+			&cli.StringFlag{
+				Name:  ARG_RESULTS_WEBHOOK_EXTRA_LABELS,
+				Value: "",
+				Usage: "Comma-separated key=val pairs merged into the webhook JSON top level, e.g. cluster=a,region=eu",
+			},
 			&cli.BoolFlag{
 				Name:    ARG_QUIET,
 				Aliases: []string{"q"},
@@ -409,6 +687,16 @@ func main() {
 				Usage:  "Fetch objects from the S3 storage",
 				Action: cmd_run,
 			},
+			{
+				Name:   "push",
+				Usage:  "Upload synthetic objects to the S3 storage",
+				Action: cmd_push,
+			},
+			{
+				Name:   "mixed",
+				Usage:  "Interleave GETs and PUTs against the S3 storage at --read-write-ratio",
+				Action: cmd_mixed,
+			},
 			{
 				Name:   "show",
 				Usage:  "Show the configuration",
@@ -417,7 +705,18 @@ func main() {
 		},
 	}
 
-	if err := cmd.Run(context.Background(), os.Args); err != nil {
+	// A first Ctrl-C (or SIGTERM) cancels the run's context so in-flight
+	// requests fail fast with contextStatusCode and displayResults still
+	// runs against whatever was collected; a second one falls through to
+	// the default Go behaviour and kills the process immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := cmd.Run(ctx, os.Args); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the Prometheus collectors fed from the same sample stream
+// collectResult already consumes, so a benchmark run can be scraped live
+// during a long run instead of only read from the end-of-run JSON blob.
+type Metrics struct {
+	objectsDownloaded *prometheus.CounterVec
+	bytesDownloaded   prometheus.Counter
+	objectDuration    prometheus.Histogram
+	objectSize        prometheus.Histogram
+	inflight          prometheus.Gauge
+}
+
+// newMetrics registers the s3tonowhere collectors against the default
+// registry.
+func newMetrics() *Metrics {
+	return &Metrics{
+		objectsDownloaded: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "s3tonowhere_objects_downloaded_total",
+			Help: "Total number of objects downloaded, labelled by final HTTP status code.",
+		}, []string{"status"}),
+		bytesDownloaded: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "s3tonowhere_bytes_downloaded_total",
+			Help: "Total number of bytes downloaded.",
+		}),
+		objectDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+			Name:    "s3tonowhere_object_duration_seconds",
+			Help:    "Per-object download duration in seconds.",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}),
+		objectSize: promauto.NewHistogram(prometheus.HistogramOpts{
+			Name:    "s3tonowhere_object_size_bytes",
+			Help:    "Per-object size in bytes.",
+			Buckets: prometheus.ExponentialBuckets(1024, 4, 12),
+		}),
+		inflight: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "s3tonowhere_inflight_downloads",
+			Help: "Number of object downloads currently in flight.",
+		}),
+	}
+}
+
+// observe records a completed whole-object sample; chunk-level samples
+// (see chunk.go) are skipped since they would double count bytes and
+// durations already reflected in the whole-object sample they roll up
+// into. inflight is read from the same shared_counter collectResult
+// already has, so there is no extra synchronization path.
+func (m *Metrics) observe(sample ChannelSample, inflight int) {
+	if sample.chunk {
+		return
+	}
+	m.objectsDownloaded.WithLabelValues(strconv.Itoa(sample.statusCode)).Inc()
+	m.bytesDownloaded.Add(float64(sample.size))
+	m.objectDuration.Observe(sample.duration.Seconds())
+	m.objectSize.Observe(float64(sample.size))
+	m.inflight.Set(float64(inflight))
+}
+
+// startMetricsServer starts an HTTP server exposing /metrics in the
+// background and returns it so the caller can shut it down once the run
+// completes.
+func startMetricsServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		log.Info("Serving Prometheus metrics", "addr", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("Metrics server stopped", "error", err)
+		}
+	}()
+	return server
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/minio/minio-go/v7"
+)
+
+// contextStatusCode is the synthetic HTTP status recorded for a request
+// that was aborted by ctx rather than failing against S3: --limit-duration
+// expiring or Ctrl-C (see signal.NotifyContext in main). It mirrors nginx's
+// convention for client-closed-request so it reads naturally next to the
+// other entries in http_status.
+const contextStatusCode = 499
+
+// statusForContextErr reports whether ctx has been cancelled or its
+// deadline exceeded, returning the synthetic status to record in that case.
+// Callers check this before falling back to minio.ToErrorResponse, since a
+// context error isn't a real S3 response and isRetryableError already
+// treats it as non-retryable.
+func statusForContextErr(ctx context.Context) (int, bool) {
+	if ctx.Err() != nil {
+		return contextStatusCode, true
+	}
+	return 0, false
+}
+
+// isRetryableError reports whether err represents a transient condition
+// worth retrying: a timeout/temporary network error, a 5xx response, or one
+// of the S3 error codes known to be transient under load. 404/403-style
+// errors fall through as non-retryable so callers record and move on.
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && (netErr.Timeout() || netErr.Temporary()) {
+		return true
+	}
+	resp := minio.ToErrorResponse(err)
+	if resp.StatusCode >= 500 {
+		return true
+	}
+	switch resp.Code {
+	case "SlowDown", "RequestTimeout", "InternalError":
+		return true
+	}
+	return false
+}
+
+// backoffDelay computes a full-jitter exponential backoff delay for the
+// given attempt: a random duration in [0, min(max, base * 2^attempt)].
+func backoffDelay(attempt int, base, max time.Duration) time.Duration {
+	cap := base << attempt
+	if cap <= 0 || cap > max {
+		cap = max
+	}
+	if cap <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(cap) + 1))
+}
+
+// withRetry runs attempt until it succeeds, returns a non-retryable error,
+// or config.MaxRetries retries (MaxRetries+1 attempts in all) have been
+// made, sleeping with full-jitter
+// exponential backoff between tries. It reports the status code of the
+// first and final attempts plus how many retries were needed, so callers
+// can tell whether a sample recovered from a transient error. ctx is
+// watched during the backoff sleep so a cancelled/expired context (see
+// statusForContextErr) aborts a pending retry immediately instead of
+// sleeping out the full delay, keeping shutdown on --limit-duration or
+// Ctrl-C prompt.
+func withRetry(ctx context.Context, config *Config, attempt func() (statusCode int, err error)) (firstStatusCode int, finalStatusCode int, retries int) {
+	base := time.Duration(config.RetryBaseDelay) * time.Millisecond
+	maxDelay := time.Duration(config.RetryMaxDelay) * time.Millisecond
+	for {
+		statusCode, err := attempt()
+		if retries == 0 {
+			firstStatusCode = statusCode
+		}
+		if err == nil || !isRetryableError(err) || retries >= config.MaxRetries {
+			finalStatusCode = statusCode
+			return
+		}
+		delay := backoffDelay(retries, base, maxDelay)
+		log.Debug("Retrying after transient error", "attempt", retries+1, "delay", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			finalStatusCode = contextStatusCode
+			return
+		}
+		retries++
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// fakeNetError implements net.Error so isRetryableError's net.Error branch
+// can be exercised without opening a real connection.
+type fakeNetError struct {
+	timeout   bool
+	temporary bool
+}
+
+func (e fakeNetError) Error() string   { return "fake net error" }
+func (e fakeNetError) Timeout() bool   { return e.timeout }
+func (e fakeNetError) Temporary() bool { return e.temporary }
+
+func TestBackoffDelayBounds(t *testing.T) {
+	base := 10 * time.Millisecond
+	max := 1 * time.Second
+	for attempt := 0; attempt < 10; attempt++ {
+		for i := 0; i < 20; i++ {
+			delay := backoffDelay(attempt, base, max)
+			if delay < 0 {
+				t.Fatalf("attempt %d: delay %v is negative", attempt, delay)
+			}
+			if delay > max {
+				t.Fatalf("attempt %d: delay %v exceeds max %v", attempt, delay, max)
+			}
+		}
+	}
+}
+
+func TestBackoffDelayCapsAtMax(t *testing.T) {
+	base := 1 * time.Second
+	max := 2 * time.Second
+	// base << attempt overflows well past max at a high attempt count, so
+	// the result must still be clamped to [0, max].
+	for i := 0; i < 20; i++ {
+		delay := backoffDelay(40, base, max)
+		if delay > max {
+			t.Fatalf("delay %v exceeds max %v", delay, max)
+		}
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	if isRetryableError(nil) {
+		t.Fatal("nil error should not be retryable")
+	}
+}
+
+func TestIsRetryableErrorStatusCode(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		want       bool
+	}{
+		{"5xx is retryable", 503, true},
+		{"500 is retryable", 500, true},
+		{"404 is not retryable", 404, false},
+		{"403 is not retryable", 403, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := minio.ErrorResponse{StatusCode: c.statusCode}
+			if got := isRetryableError(err); got != c.want {
+				t.Fatalf("isRetryableError(status %d) = %v, want %v", c.statusCode, got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryableErrorCode(t *testing.T) {
+	cases := []struct {
+		code string
+		want bool
+	}{
+		{"SlowDown", true},
+		{"RequestTimeout", true},
+		{"InternalError", true},
+		{"NoSuchKey", false},
+		{"AccessDenied", false},
+	}
+	for _, c := range cases {
+		t.Run(c.code, func(t *testing.T) {
+			err := minio.ErrorResponse{Code: c.code, StatusCode: 400}
+			if got := isRetryableError(err); got != c.want {
+				t.Fatalf("isRetryableError(code %q) = %v, want %v", c.code, got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryableErrorNetError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timeout", fakeNetError{timeout: true}, true},
+		{"temporary", fakeNetError{temporary: true}, true},
+		{"neither", fakeNetError{}, false},
+		{"wrapped timeout", fmt.Errorf("dial: %w", fakeNetError{timeout: true}), true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isRetryableError(c.err); got != c.want {
+				t.Fatalf("isRetryableError(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryableErrorGenericErrorIsNotRetryable(t *testing.T) {
+	if isRetryableError(errors.New("boom")) {
+		t.Fatal("a plain error unrelated to S3 or networking should not be retryable")
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// parseExtraLabels parses a comma-separated key=val,key2=val2 list into a
+// map, so multiple benchmark runners in a fleet (cluster, region, run-id)
+// can be told apart in the webhook payload.
+func parseExtraLabels(raw string) map[string]string {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		labels[key] = strings.TrimSpace(value)
+	}
+	return labels
+}
+
+// postResult POSTs the result JSON, merged with any extra labels, to
+// config.ResultsWebhookURL with an auth token in config.ResultsWebhookAuthHeader.
+// It retries on 5xx responses using the same full-jitter backoff as the
+// download retries (see retry.go), with a 30s timeout per attempt.
+func postResult(config *Config, data []byte) {
+	if config.ResultsWebhookURL == "" {
+		return
+	}
+
+	merged := make(map[string]any)
+	if err := json.Unmarshal(data, &merged); err != nil {
+		log.Error("Failed to prepare results webhook payload", "error", err)
+		return
+	}
+	for key, value := range parseExtraLabels(config.ResultsWebhookExtraLabels) {
+		merged[key] = value
+	}
+	payload, err := json.Marshal(merged)
+	if err != nil {
+		log.Error("Failed to marshal results webhook payload", "error", err)
+		return
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	base := time.Duration(config.RetryBaseDelay) * time.Millisecond
+	maxDelay := time.Duration(config.RetryMaxDelay) * time.Millisecond
+
+	for attempt := 0; ; attempt++ {
+		statusCode, err := postResultOnce(client, config, payload)
+		if err == nil {
+			if attempt > 0 {
+				log.Info("Results webhook recovered after retries", "attempt", attempt+1)
+			}
+			return
+		}
+		if statusCode < 500 || attempt >= config.MaxRetries {
+			log.Error("Failed to post results webhook", "error", err, "status", statusCode)
+			return
+		}
+		delay := backoffDelay(attempt, base, maxDelay)
+		log.Debug("Retrying results webhook after 5xx", "attempt", attempt+1, "delay", delay, "error", err)
+		time.Sleep(delay)
+	}
+}
+
+// postResultOnce issues a single webhook POST attempt.
+func postResultOnce(client *http.Client, config *Config, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, config.ResultsWebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if config.ResultsWebhookAuthToken != "" {
+		req.Header.Set(config.ResultsWebhookAuthHeader, "Bearer "+config.ResultsWebhookAuthToken)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, fmt.Errorf("results webhook returned %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
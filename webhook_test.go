@@ -0,0 +1,29 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExtraLabels(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		want map[string]string
+	}{
+		{"empty", "", map[string]string{}},
+		{"single", "cluster=a", map[string]string{"cluster": "a"}},
+		{"multiple", "cluster=a,region=eu", map[string]string{"cluster": "a", "region": "eu"}},
+		{"trims whitespace", " cluster = a , region=eu ", map[string]string{"cluster": "a", "region": "eu"}},
+		{"skips entries without =", "cluster=a,bogus,region=eu", map[string]string{"cluster": "a", "region": "eu"}},
+		{"skips empty key", "=a,region=eu", map[string]string{"region": "eu"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := parseExtraLabels(c.raw)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("parseExtraLabels(%q) = %v, want %v", c.raw, got, c.want)
+			}
+		})
+	}
+}
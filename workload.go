@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	mrand "math/rand"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// newS3Client builds the minio client shared by every workload mode
+// (fetch, push, mixed).
+func newS3Client(config *Config) (*minio.Client, error) {
+	return minio.New(config.S3Host,
+		&minio.Options{
+			Creds:     credentials.NewStaticV4(config.S3AccessKey, config.S3SecretKey, ""),
+			Secure:    config.UseSSL,
+			Transport: newTransport(),
+		})
+}
+
+// generatedKey returns a bench/{shard}/{seq} key. Keys are hashed into a
+// shard rather than assigned sequentially so the generated workload spreads
+// across S3 partitions instead of hammering whichever one holds the
+// current prefix.
+func generatedKey(seq int, shards int) string {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d", seq)
+	shard := int(h.Sum32() % uint32(shards))
+	return fmt.Sprintf("bench/%02x/%d", shard, seq)
+}
+
+// objectKeys returns a channel of keys to operate on, sourced according to
+// config.KeySource. "generated" and "file" let push/mixed workloads start
+// immediately instead of waiting on a bucket listing.
+func objectKeys(ctx context.Context, config *Config, s3Client *minio.Client) (<-chan string, error) {
+	keys := make(chan string)
+
+	switch config.KeySource {
+	case "file":
+		f, err := os.Open(config.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			defer f.Close()
+			defer close(keys)
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				line := scanner.Text()
+				if line == "" {
+					continue
+				}
+				select {
+				case keys <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				log.Error("Failed to read key file", "path", config.KeyFile, "error", err)
+			}
+		}()
+	case "generated":
+		go func() {
+			defer close(keys)
+			for seq := 0; config.LimitObjects <= 0 || seq < config.LimitObjects; seq++ {
+				select {
+				case keys <- generatedKey(seq, config.KeyShards):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	default: // "list"
+		opts := minio.ListObjectsOptions{
+			Recursive:       true,
+			ReverseVersions: false,
+			WithVersions:    false,
+			WithMetadata:    false,
+			MaxKeys:         3000, // <1000 Causes more fetches but reduces memory usage.
+		}
+		go func() {
+			defer close(keys)
+			for object := range s3Client.ListObjects(ctx, config.S3BucketName, opts) {
+				if object.Err != nil {
+					log.Error(object.Err)
+					return
+				}
+				select {
+				case keys <- object.Key:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	return keys, nil
+}
+
+// objectSize picks a synthetic object size for the push workload according
+// to config.ObjectSizeDist.
+func objectSize(config *Config) int64 {
+	switch config.ObjectSizeDist {
+	case "uniform":
+		span := config.ObjectSizeMax - config.ObjectSizeMin
+		if span <= 0 {
+			return config.ObjectSizeMin
+		}
+		return config.ObjectSizeMin + mrand.Int63n(span)
+	case "lognormal":
+		size := int64(math.Exp(config.ObjectSizeMean + config.ObjectSizeSigma*mrand.NormFloat64()))
+		if size < 1 {
+			size = 1
+		}
+		return size
+	default: // "fixed"
+		return config.ObjectSizeMin
+	}
+}
+
+// uploadS3Object PUTs a synthetic object of a size drawn from
+// config.ObjectSizeDist, filled with random bytes from crypto/rand so the
+// store can't compress or dedupe it away. This function is called within a
+// goroutine, mirroring downloadS3Object.
+func uploadS3Object(client *minio.Client, ctx context.Context, config *Config, bucketName string, key string, channel chan<- ChannelSample) {
+	start := time.Now()
+	size := objectSize(config)
+	firstStatus, finalStatus, retries := withRetry(ctx, config, func() (int, error) {
+		_, err := client.PutObject(ctx, bucketName, key, io.LimitReader(rand.Reader, size), size, minio.PutObjectOptions{
+			ContentType: "application/octet-stream",
+		})
+		if err != nil {
+			if status, ok := statusForContextErr(ctx); ok {
+				return status, ctx.Err()
+			}
+			resp := minio.ToErrorResponse(err)
+			log.Error("Failed to upload", "key", key, "error", err)
+			return resp.StatusCode, err
+		}
+		return 200, nil
+	})
+	channel <- ChannelSample{size: uint64(size), objectName: key, statusCode: finalStatus, firstStatusCode: firstStatus, retries: retries, duration: time.Since(start), op: "PUT"}
+}
+
+// walkPushObjects generates synthetic keys and uploads an object for each
+// one; it is the push-subcommand counterpart to walkBucketObjects.
+func walkPushObjects(ctx context.Context, config *Config, samples chan<- ChannelSample, counter *int) {
+	ctx, cancel := workloadContext(ctx, config)
+	defer cancel()
+
+	s3Client, err := newS3Client(config)
+	if err != nil {
+		log.Error("Failed to create S3 client", "error", err)
+		return
+	}
+
+	keys, err := objectKeys(ctx, config, s3Client)
+	if err != nil {
+		log.Error("Failed to set up key source", "error", err)
+		return
+	}
+
+	dispatchKeys(ctx, cancel, config, keys, counter, func(ctx context.Context, key string) {
+		uploadS3Object(s3Client, ctx, config, config.S3BucketName, key, samples)
+	})
+}
+
+// walkMixedObjects interleaves GETs and PUTs at config.ReadWriteRatio: for
+// each key pulled off the key source, a GET is issued with that
+// probability and a PUT otherwise. It shares downloadS3Object and
+// uploadS3Object with the fetch and push workloads so ChannelSamples, and
+// therefore summariseDownloads, are identical across all three modes.
+func walkMixedObjects(ctx context.Context, config *Config, samples chan<- ChannelSample, counter *int) {
+	ctx, cancel := workloadContext(ctx, config)
+	defer cancel()
+
+	s3Client, err := newS3Client(config)
+	if err != nil {
+		log.Error("Failed to create S3 client", "error", err)
+		return
+	}
+
+	keys, err := objectKeys(ctx, config, s3Client)
+	if err != nil {
+		log.Error("Failed to set up key source", "error", err)
+		return
+	}
+
+	var arena *arenaPool
+	if config.ChunksPerObject > 1 {
+		arena = newArenaPool(config.ChunkSize)
+	}
+
+	dispatchKeys(ctx, cancel, config, keys, counter, func(ctx context.Context, key string) {
+		if mrand.Float64() < config.ReadWriteRatio {
+			downloadS3Object(s3Client, ctx, config, arena, config.S3BucketName, key, samples)
+		} else {
+			uploadS3Object(s3Client, ctx, config, config.S3BucketName, key, samples)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestGeneratedKeyIsDeterministicAndUnique(t *testing.T) {
+	key := generatedKey(42, 16)
+	again := generatedKey(42, 16)
+	if key != again {
+		t.Fatalf("generatedKey is not deterministic: %q != %q", key, again)
+	}
+
+	seen := make(map[string]bool)
+	for seq := 0; seq < 1000; seq++ {
+		k := generatedKey(seq, 16)
+		if seen[k] {
+			t.Fatalf("generatedKey(%d, 16) collided with an earlier sequence: %q", seq, k)
+		}
+		seen[k] = true
+	}
+}
+
+func TestObjectSizeFixed(t *testing.T) {
+	config := &Config{ObjectSizeDist: "fixed", ObjectSizeMin: 1024}
+	if got := objectSize(config); got != 1024 {
+		t.Fatalf("objectSize(fixed) = %d, want 1024", got)
+	}
+}
+
+func TestObjectSizeUniformWithinBounds(t *testing.T) {
+	config := &Config{ObjectSizeDist: "uniform", ObjectSizeMin: 100, ObjectSizeMax: 200}
+	for i := 0; i < 100; i++ {
+		got := objectSize(config)
+		if got < config.ObjectSizeMin || got >= config.ObjectSizeMax {
+			t.Fatalf("objectSize(uniform) = %d, want in [%d, %d)", got, config.ObjectSizeMin, config.ObjectSizeMax)
+		}
+	}
+}
+
+func TestObjectSizeUniformDegenerateSpan(t *testing.T) {
+	config := &Config{ObjectSizeDist: "uniform", ObjectSizeMin: 100, ObjectSizeMax: 100}
+	if got := objectSize(config); got != 100 {
+		t.Fatalf("objectSize(uniform, zero span) = %d, want 100", got)
+	}
+}
+
+func TestObjectSizeLognormalPositive(t *testing.T) {
+	config := &Config{ObjectSizeDist: "lognormal", ObjectSizeMean: 10, ObjectSizeSigma: 1}
+	for i := 0; i < 100; i++ {
+		if got := objectSize(config); got < 1 {
+			t.Fatalf("objectSize(lognormal) = %d, want >= 1", got)
+		}
+	}
+}